@@ -0,0 +1,352 @@
+package httpx
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachedResponse 是存入 CacheStore 的响应快照，Body 是已完整读取的字节，支持多次安全重放
+type CachedResponse struct {
+	StatusCode  int
+	Status      string
+	Header      http.Header
+	Body        []byte
+	StoredAt    time.Time
+	VaryHeaders map[string]string // Vary 中列出的请求头名 -> 存储时的请求头取值
+}
+
+// toResponse 基于缓存快照重建一个 *http.Response，Body 包装为可重复读取的 bytes.Reader
+func (e *CachedResponse) toResponse(req *http.Request) *http.Response {
+	header := e.Header.Clone()
+	header.Set("Age", strconv.Itoa(int(time.Since(e.StoredAt).Seconds())))
+	return &http.Response{
+		Status:        e.Status,
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// CacheStore 是响应缓存的存储抽象，内置 LRU 实现之外可接入 Redis/磁盘等
+type CacheStore interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, resp *CachedResponse, ttl time.Duration)
+	Delete(key string)
+}
+
+// CacheOptions 配置 CacheMiddleware
+type CacheOptions struct {
+	// DefaultTTL 响应未提供 max-age/Expires 等新鲜度信息时使用的兜底 TTL，为 0 则此类响应不缓存
+	DefaultTTL time.Duration
+}
+
+// lruCacheEntry 是 lruCacheStore 链表节点承载的数据
+type lruCacheEntry struct {
+	key      string
+	resp     *CachedResponse
+	expireAt time.Time // 零值表示不因 TTL 过期，仅受 LRU 容量淘汰
+}
+
+// lruCacheStore 默认的进程内 LRU CacheStore 实现
+type lruCacheStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+}
+
+// NewLRUCacheStore 创建一个默认的进程内 LRU CacheStore，maxEntries<=0 表示不限制条目数
+func NewLRUCacheStore(maxEntries int) CacheStore {
+	return &lruCacheStore{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+func (s *lruCacheStore) Get(key string) (*CachedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*lruCacheEntry)
+	if !entry.expireAt.IsZero() && time.Now().After(entry.expireAt) {
+		s.removeElement(el)
+		return nil, false
+	}
+	s.ll.MoveToFront(el)
+	return entry.resp, true
+}
+
+func (s *lruCacheStore) Set(key string, resp *CachedResponse, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = time.Now().Add(ttl)
+	}
+
+	if el, ok := s.items[key]; ok {
+		s.ll.MoveToFront(el)
+		entry := el.Value.(*lruCacheEntry)
+		entry.resp = resp
+		entry.expireAt = expireAt
+		return
+	}
+
+	el := s.ll.PushFront(&lruCacheEntry{key: key, resp: resp, expireAt: expireAt})
+	s.items[key] = el
+	if s.maxEntries > 0 && s.ll.Len() > s.maxEntries {
+		if oldest := s.ll.Back(); oldest != nil {
+			s.removeElement(oldest)
+		}
+	}
+}
+
+func (s *lruCacheStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.items[key]; ok {
+		s.removeElement(el)
+	}
+}
+
+func (s *lruCacheStore) removeElement(el *list.Element) {
+	s.ll.Remove(el)
+	delete(s.items, el.Value.(*lruCacheEntry).key)
+}
+
+// cacheControl 是解析后的 Cache-Control 指令集合
+type cacheControl struct {
+	maxAge         int
+	hasMaxAge      bool
+	noStore        bool
+	noCache        bool
+	private        bool
+	public         bool
+	mustRevalidate bool
+}
+
+func parseCacheControl(header string) cacheControl {
+	var cc cacheControl
+	for _, directive := range strings.Split(header, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+
+		switch name {
+		case "no-store":
+			cc.noStore = true
+		case "no-cache":
+			cc.noCache = true
+		case "private":
+			cc.private = true
+		case "public":
+			cc.public = true
+		case "must-revalidate":
+			cc.mustRevalidate = true
+		case "max-age":
+			if n, err := strconv.Atoi(value); err == nil {
+				cc.maxAge = n
+				cc.hasMaxAge = true
+			}
+		}
+	}
+	return cc
+}
+
+// freshnessTTL 依据 Cache-Control（max-age）、Age、Expires 计算剩余新鲜时长，都缺失时退回 defaultTTL
+func freshnessTTL(header http.Header, defaultTTL time.Duration) time.Duration {
+	cc := parseCacheControl(header.Get("Cache-Control"))
+	if cc.noStore {
+		return 0
+	}
+	if cc.hasMaxAge {
+		ttl := time.Duration(cc.maxAge) * time.Second
+		if age, ok := parseAge(header.Get("Age")); ok {
+			ttl -= age
+		}
+		if ttl < 0 {
+			ttl = 0
+		}
+		return ttl
+	}
+	if exp := header.Get("Expires"); exp != "" {
+		if t, err := http.ParseTime(exp); err == nil {
+			return time.Until(t)
+		}
+	}
+	return defaultTTL
+}
+
+func parseAge(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	secs, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(secs) * time.Second, true
+}
+
+// isFresh 判断缓存条目是否仍然新鲜；no-cache 要求每次使用前都重新验证
+func isFresh(header http.Header, storedAt time.Time) bool {
+	if parseCacheControl(header.Get("Cache-Control")).noCache {
+		return false
+	}
+	ttl := freshnessTTL(header, 0)
+	return ttl > 0 && time.Since(storedAt) < ttl
+}
+
+// cacheKey 由请求方法和完整 URL 构成缓存键；Vary 命中的请求头在 varyMatches 中单独比对
+func cacheKey(req *http.Request) string {
+	return req.Method + " " + req.URL.String()
+}
+
+func varyMatches(entry *CachedResponse, req *http.Request) bool {
+	for name, val := range entry.VaryHeaders {
+		if req.Header.Get(name) != val {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheTransport 缓存传输层
+type cacheTransport struct {
+	base  http.RoundTripper
+	store CacheStore
+	opts  CacheOptions
+}
+
+// CacheMiddleware 遵循 RFC 7234 的主要缓存/校验指令：新鲜命中直接返回，陈旧条目发起条件请求，
+// 304 响应被提升为 200。作为 Middleware 通过 WithMiddleware 注册时会被包裹在重试层之外，
+// 保证缓存命中不会被重试逻辑重复消耗。
+func CacheMiddleware(store CacheStore, opts CacheOptions) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &cacheTransport{base: next, store: store, opts: opts}
+	}
+}
+
+func (ct *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return ct.base.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	if entry, ok := ct.store.Get(key); ok && varyMatches(entry, req) {
+		if isFresh(entry.Header, entry.StoredAt) {
+			return entry.toResponse(req), nil
+		}
+		return ct.revalidate(req, key, entry)
+	}
+
+	resp, err := ct.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	ct.maybeStore(req, key, resp)
+	return resp, nil
+}
+
+// Unwrap 暴露被包裹的下一层传输，供 findCircuitBreaker 等观测性代码穿透中间件链
+func (ct *cacheTransport) Unwrap() http.RoundTripper {
+	return ct.base
+}
+
+// revalidate 使用存储的 ETag/Last-Modified 发起条件请求；304 时将缓存条目提升为 200 返回
+func (ct *cacheTransport) revalidate(req *http.Request, key string, entry *CachedResponse) (*http.Response, error) {
+	condReq := req.Clone(req.Context())
+	if etag := entry.Header.Get("ETag"); etag != "" {
+		condReq.Header.Set("If-None-Match", etag)
+	}
+	if lastModified := entry.Header.Get("Last-Modified"); lastModified != "" {
+		condReq.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	resp, err := ct.base.RoundTrip(condReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		entry.Header = mergeHeaders(entry.Header, resp.Header)
+		entry.StoredAt = time.Now()
+		ct.store.Set(key, entry, freshnessTTL(entry.Header, ct.opts.DefaultTTL))
+		return entry.toResponse(req), nil
+	}
+
+	ct.maybeStore(req, key, resp)
+	return resp, nil
+}
+
+// mergeHeaders 用 304 响应携带的头覆盖缓存条目中的同名头，其余沿用旧值（RFC 7234 §4.3.4）
+func mergeHeaders(stored, fresh http.Header) http.Header {
+	merged := stored.Clone()
+	for name, values := range fresh {
+		merged[name] = values
+	}
+	return merged
+}
+
+// maybeStore 依据 Cache-Control/Authorization 判断响应是否可缓存，可缓存时读取并缓冲响应体
+func (ct *cacheTransport) maybeStore(req *http.Request, key string, resp *http.Response) {
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+
+	cc := parseCacheControl(resp.Header.Get("Cache-Control"))
+	if cc.noStore || cc.private {
+		return
+	}
+	if req.Header.Get("Authorization") != "" && !cc.public {
+		return
+	}
+
+	ttl := freshnessTTL(resp.Header, ct.opts.DefaultTTL)
+	if ttl <= 0 && !cc.noCache {
+		return
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	varyHeaders := map[string]string{}
+	for _, name := range strings.Split(resp.Header.Get("Vary"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || name == "*" {
+			continue
+		}
+		varyHeaders[name] = req.Header.Get(name)
+	}
+
+	ct.store.Set(key, &CachedResponse{
+		StatusCode:  resp.StatusCode,
+		Status:      resp.Status,
+		Header:      resp.Header.Clone(),
+		Body:        body,
+		StoredAt:    time.Now(),
+		VaryHeaders: varyHeaders,
+	}, ttl)
+}
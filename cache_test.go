@@ -0,0 +1,218 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCacheControl(t *testing.T) {
+	cc := parseCacheControl(`no-cache, max-age=30, must-revalidate, private`)
+	if !cc.noCache || !cc.mustRevalidate || !cc.private {
+		t.Fatalf("unexpected directives: %+v", cc)
+	}
+	if !cc.hasMaxAge || cc.maxAge != 30 {
+		t.Fatalf("expected max-age=30, got %+v", cc)
+	}
+	if cc.noStore || cc.public {
+		t.Fatalf("unexpected directives set: %+v", cc)
+	}
+}
+
+func TestFreshnessTTLPrefersMaxAgeOverExpires(t *testing.T) {
+	h := make(http.Header)
+	h.Set("Cache-Control", "max-age=100")
+	h.Set("Age", "40")
+	h.Set("Expires", time.Now().Add(time.Hour).Format(http.TimeFormat))
+
+	ttl := freshnessTTL(h, 0)
+	if ttl != 60*time.Second {
+		t.Fatalf("expected ttl of 60s accounting for Age, got %v", ttl)
+	}
+}
+
+func TestFreshnessTTLFallsBackToExpires(t *testing.T) {
+	h := make(http.Header)
+	h.Set("Expires", time.Now().Add(30*time.Second).Format(http.TimeFormat))
+
+	ttl := freshnessTTL(h, 0)
+	if ttl <= 0 || ttl > 30*time.Second {
+		t.Fatalf("expected ttl close to 30s, got %v", ttl)
+	}
+}
+
+func TestFreshnessTTLNoStoreIsZero(t *testing.T) {
+	h := make(http.Header)
+	h.Set("Cache-Control", "no-store, max-age=100")
+
+	if ttl := freshnessTTL(h, time.Hour); ttl != 0 {
+		t.Fatalf("expected no-store to force ttl=0, got %v", ttl)
+	}
+}
+
+func TestIsFreshHonorsNoCache(t *testing.T) {
+	h := make(http.Header)
+	h.Set("Cache-Control", "no-cache, max-age=100")
+
+	if isFresh(h, time.Now()) {
+		t.Fatal("expected no-cache entry to never be considered fresh")
+	}
+}
+
+func TestLRUCacheStoreEvictsOldestOnOverflow(t *testing.T) {
+	store := NewLRUCacheStore(2).(*lruCacheStore)
+
+	store.Set("a", &CachedResponse{StatusCode: 200}, time.Hour)
+	store.Set("b", &CachedResponse{StatusCode: 200}, time.Hour)
+	store.Set("c", &CachedResponse{StatusCode: 200}, time.Hour) // evicts "a"
+
+	if _, ok := store.Get("a"); ok {
+		t.Fatal("expected oldest entry to be evicted once capacity is exceeded")
+	}
+	if _, ok := store.Get("b"); !ok {
+		t.Fatal("expected b to still be cached")
+	}
+	if _, ok := store.Get("c"); !ok {
+		t.Fatal("expected c to still be cached")
+	}
+}
+
+func TestLRUCacheStoreExpiresByTTL(t *testing.T) {
+	store := NewLRUCacheStore(10)
+	store.Set("k", &CachedResponse{StatusCode: 200}, 10*time.Millisecond)
+
+	if _, ok := store.Get("k"); !ok {
+		t.Fatal("expected entry to be present before ttl elapses")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := store.Get("k"); ok {
+		t.Fatal("expected entry to have expired")
+	}
+}
+
+func TestCacheTransportServesFreshHitWithoutHittingBase(t *testing.T) {
+	calls := 0
+	base := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		h := make(http.Header)
+		h.Set("Cache-Control", "max-age=60")
+		return &http.Response{StatusCode: 200, Header: h, Body: io.NopCloser(strings.NewReader("payload"))}, nil
+	})
+
+	ct := CacheMiddleware(NewLRUCacheStore(10), CacheOptions{})(base)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/resource", nil)
+	resp1, err := ct.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	io.ReadAll(resp1.Body)
+	resp1.Body.Close()
+
+	resp2, err := ct.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp2.Body)
+	resp2.Body.Close()
+
+	if calls != 1 {
+		t.Fatalf("expected base transport to be hit once, got %d calls", calls)
+	}
+	if string(body) != "payload" {
+		t.Fatalf("unexpected cached body: %q", body)
+	}
+}
+
+func TestCacheTransportRevalidatesStaleEntryAndPromotes304(t *testing.T) {
+	calls := 0
+	base := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			h := make(http.Header)
+			h.Set("Cache-Control", "no-cache")
+			h.Set("ETag", `"v1"`)
+			return &http.Response{StatusCode: 200, Header: h, Body: io.NopCloser(strings.NewReader("stale body"))}, nil
+		}
+
+		if req.Header.Get("If-None-Match") != `"v1"` {
+			t.Fatalf("expected conditional request with If-None-Match, got headers %v", req.Header)
+		}
+		h := make(http.Header)
+		h.Set("Cache-Control", "max-age=60")
+		return &http.Response{StatusCode: http.StatusNotModified, Header: h, Body: http.NoBody}, nil
+	})
+
+	ct := CacheMiddleware(NewLRUCacheStore(10), CacheOptions{})(base)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/resource", nil)
+
+	resp1, err := ct.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body1, _ := io.ReadAll(resp1.Body)
+	resp1.Body.Close()
+	if string(body1) != "stale body" {
+		t.Fatalf("unexpected first body: %q", body1)
+	}
+
+	resp2, err := ct.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body2, _ := io.ReadAll(resp2.Body)
+	resp2.Body.Close()
+
+	if calls != 2 {
+		t.Fatalf("expected exactly one revalidation request, got %d calls", calls)
+	}
+	if resp2.StatusCode != http.StatusOK {
+		t.Fatalf("expected 304 to be promoted to 200, got %d", resp2.StatusCode)
+	}
+	if string(body2) != "stale body" {
+		t.Fatalf("expected cached body to be reused after 304, got %q", body2)
+	}
+}
+
+func TestCacheTransportDoesNotStoreNoStore(t *testing.T) {
+	base := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		h := make(http.Header)
+		h.Set("Cache-Control", "no-store")
+		return &http.Response{StatusCode: 200, Header: h, Body: io.NopCloser(strings.NewReader("payload"))}, nil
+	})
+
+	store := NewLRUCacheStore(10)
+	ct := CacheMiddleware(store, CacheOptions{})(base)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/resource", nil)
+	resp, err := ct.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if _, ok := store.Get(cacheKey(req)); ok {
+		t.Fatal("expected no-store response not to be cached")
+	}
+}
+
+func TestVaryMatches(t *testing.T) {
+	entry := &CachedResponse{VaryHeaders: map[string]string{"Accept-Encoding": "gzip"}}
+
+	reqMatch, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	reqMatch.Header.Set("Accept-Encoding", "gzip")
+	if !varyMatches(entry, reqMatch) {
+		t.Fatal("expected matching Vary header to match")
+	}
+
+	reqMismatch, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	reqMismatch.Header.Set("Accept-Encoding", "br")
+	if varyMatches(entry, reqMismatch) {
+		t.Fatal("expected differing Vary header to not match")
+	}
+}
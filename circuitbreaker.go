@@ -0,0 +1,229 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrCircuitOpen 熔断器处于打开状态时返回
+var ErrCircuitOpen = errors.New("httpx: circuit breaker open")
+
+// circuitState 熔断器状态
+type circuitState int32
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// CircuitBreakerOptions 熔断器配置
+type CircuitBreakerOptions struct {
+	// FailureThreshold 滚动窗口内达到该失败次数后触发熔断
+	FailureThreshold int
+	// RollingWindow 统计失败次数的滚动窗口
+	RollingWindow time.Duration
+	// OpenTimeout 熔断打开后，多久进入半开状态尝试探测
+	OpenTimeout time.Duration
+	// MaxOpenTimeout 半开探测失败后重新打开的超时上限（每次失败翻倍，直到该上限）
+	MaxOpenTimeout time.Duration
+	// KeyFunc 从请求推导熔断器 key，默认使用 req.URL.Host
+	KeyFunc func(req *http.Request) string
+}
+
+func (o *CircuitBreakerOptions) withDefaults() *CircuitBreakerOptions {
+	out := *o
+	if out.FailureThreshold <= 0 {
+		out.FailureThreshold = 5
+	}
+	if out.RollingWindow <= 0 {
+		out.RollingWindow = 10 * time.Second
+	}
+	if out.OpenTimeout <= 0 {
+		out.OpenTimeout = 30 * time.Second
+	}
+	if out.MaxOpenTimeout <= 0 {
+		out.MaxOpenTimeout = 5 * time.Minute
+	}
+	if out.KeyFunc == nil {
+		out.KeyFunc = func(req *http.Request) string {
+			return req.URL.Host
+		}
+	}
+	return &out
+}
+
+// hostBreaker 单个 host 的熔断状态，字段均通过原子操作访问，RoundTrip 热路径无锁
+type hostBreaker struct {
+	state        int32 // circuitState
+	failures     int32
+	windowStart  int64 // unix nano，滚动窗口起点
+	openedAt     int64 // unix nano，进入 open/half-open 的时刻
+	openTimeout  int64 // 当前的打开超时（纳秒），随半开失败翻倍
+	halfOpenBusy int32 // 半开状态下是否已有探测请求在途
+}
+
+// circuitBreakerTransport 熔断传输层
+type circuitBreakerTransport struct {
+	base    http.RoundTripper
+	opts    *CircuitBreakerOptions
+	hosts   sync.Map // string -> *hostBreaker
+	nowFunc func() time.Time
+}
+
+func (cb *circuitBreakerTransport) now() time.Time {
+	if cb.nowFunc != nil {
+		return cb.nowFunc()
+	}
+	return time.Now()
+}
+
+func (cb *circuitBreakerTransport) breakerFor(key string) *hostBreaker {
+	if v, ok := cb.hosts.Load(key); ok {
+		return v.(*hostBreaker)
+	}
+	hb := &hostBreaker{openTimeout: int64(cb.opts.OpenTimeout)}
+	actual, _ := cb.hosts.LoadOrStore(key, hb)
+	return actual.(*hostBreaker)
+}
+
+func (cb *circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := cb.opts.KeyFunc(req)
+	hb := cb.breakerFor(key)
+	now := cb.now().UnixNano()
+
+	switch circuitState(atomic.LoadInt32(&hb.state)) {
+	case circuitOpen:
+		openedAt := atomic.LoadInt64(&hb.openedAt)
+		timeout := atomic.LoadInt64(&hb.openTimeout)
+		if now-openedAt < timeout {
+			return nil, ErrCircuitOpen
+		}
+		// 超时已过，尝试进入半开状态
+		if !atomic.CompareAndSwapInt32(&hb.state, int32(circuitOpen), int32(circuitHalfOpen)) {
+			// 其他 goroutine 已经切换了状态，按新状态重新判断
+			return cb.RoundTrip(req)
+		}
+		atomic.StoreInt32(&hb.halfOpenBusy, 0)
+		fallthrough
+	case circuitHalfOpen:
+		// 半开状态只允许一个探测请求通过，其余直接拒绝
+		if !atomic.CompareAndSwapInt32(&hb.halfOpenBusy, 0, 1) {
+			return nil, ErrCircuitOpen
+		}
+		resp, err := cb.base.RoundTrip(req)
+		if cb.isFailure(resp, err) {
+			cb.trip(hb, now, true)
+		} else {
+			cb.reset(hb)
+		}
+		atomic.StoreInt32(&hb.halfOpenBusy, 0)
+		return resp, err
+	}
+
+	resp, err := cb.base.RoundTrip(req)
+	if cb.isFailure(resp, err) {
+		cb.recordFailure(hb, now)
+	} else {
+		cb.reset(hb)
+	}
+	return resp, err
+}
+
+func (cb *circuitBreakerTransport) isFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	if resp == nil {
+		return false
+	}
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// recordFailure 在滚动窗口内累计失败次数，达到阈值即触发熔断
+func (cb *circuitBreakerTransport) recordFailure(hb *hostBreaker, now int64) {
+	windowStart := atomic.LoadInt64(&hb.windowStart)
+	if now-windowStart > int64(cb.opts.RollingWindow) {
+		atomic.StoreInt64(&hb.windowStart, now)
+		atomic.StoreInt32(&hb.failures, 1)
+		if int32(cb.opts.FailureThreshold) <= 1 {
+			cb.trip(hb, now, false)
+		}
+		return
+	}
+	if atomic.AddInt32(&hb.failures, 1) >= int32(cb.opts.FailureThreshold) {
+		cb.trip(hb, now, false)
+	}
+}
+
+// trip 触发/重新触发熔断；fromHalfOpen 为 true 时对打开超时做指数翻倍
+func (cb *circuitBreakerTransport) trip(hb *hostBreaker, now int64, fromHalfOpen bool) {
+	timeout := atomic.LoadInt64(&hb.openTimeout)
+	if fromHalfOpen {
+		timeout *= 2
+		if max := int64(cb.opts.MaxOpenTimeout); timeout > max {
+			timeout = max
+		}
+		atomic.StoreInt64(&hb.openTimeout, timeout)
+	}
+	atomic.StoreInt64(&hb.openedAt, now)
+	atomic.StoreInt32(&hb.state, int32(circuitOpen))
+}
+
+func (cb *circuitBreakerTransport) reset(hb *hostBreaker) {
+	atomic.StoreInt32(&hb.state, int32(circuitClosed))
+	atomic.StoreInt32(&hb.failures, 0)
+	atomic.StoreInt64(&hb.openTimeout, int64(cb.opts.OpenTimeout))
+}
+
+// CircuitBreakerMiddleware 按 host 维护熔断状态，避免向已知不健康的下游持续发请求
+func CircuitBreakerMiddleware(opts CircuitBreakerOptions) Middleware {
+	resolved := opts.withDefaults()
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &circuitBreakerTransport{base: next, opts: resolved}
+	}
+}
+
+// CircuitState 返回指定 host 当前的熔断状态，用于可观测性
+func (c *Client) CircuitState(host string) (state string, ok bool) {
+	cb, ok := findCircuitBreaker(c.httpClient.Transport)
+	if !ok {
+		return "", false
+	}
+	v, ok := cb.hosts.Load(host)
+	if !ok {
+		return "closed", true
+	}
+	switch circuitState(atomic.LoadInt32(&v.(*hostBreaker).state)) {
+	case circuitOpen:
+		return "open", true
+	case circuitHalfOpen:
+		return "half-open", true
+	default:
+		return "closed", true
+	}
+}
+
+// findCircuitBreaker 沿中间件链查找熔断传输层。链上的传输层只要实现了 roundTripperUnwrapper
+// 即可被穿透，不需要在这里为每种中间件类型单独列举。
+func findCircuitBreaker(rt http.RoundTripper) (*circuitBreakerTransport, bool) {
+	for rt != nil {
+		if cb, ok := rt.(*circuitBreakerTransport); ok {
+			return cb, true
+		}
+		u, ok := rt.(roundTripperUnwrapper)
+		if !ok {
+			return nil, false
+		}
+		rt = u.Unwrap()
+	}
+	return nil, false
+}
+
+// Unwrap 暴露被包裹的下一层传输，供 findCircuitBreaker 等观测性代码穿透中间件链
+func (cb *circuitBreakerTransport) Unwrap() http.RoundTripper {
+	return cb.base
+}
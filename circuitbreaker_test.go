@@ -0,0 +1,139 @@
+package httpx
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+type fakeRoundTripper struct {
+	fn func(req *http.Request) (*http.Response, error)
+}
+
+func (f fakeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f.fn(req)
+}
+
+func mustReq(t *testing.T, host string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, "http://"+host+"/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func newCircuitBreaker(opts CircuitBreakerOptions, rt http.RoundTripper) *circuitBreakerTransport {
+	return &circuitBreakerTransport{base: rt, opts: opts.withDefaults()}
+}
+
+// TestCircuitBreakerTripsOnSingleFailure 是对 FailureThreshold=1 时需要两次失败才触发熔断的回归测试：
+// 任何 host 的第一次失败都会命中窗口重置分支，必须在那里也做阈值检查。
+func TestCircuitBreakerTripsOnSingleFailure(t *testing.T) {
+	calls := 0
+	cb := newCircuitBreaker(
+		CircuitBreakerOptions{FailureThreshold: 1, RollingWindow: time.Second, OpenTimeout: time.Minute},
+		fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+			calls++
+			return &http.Response{StatusCode: 500}, nil
+		}},
+	)
+
+	req := mustReq(t, "example.com")
+
+	if _, err := cb.RoundTrip(req); err != nil {
+		t.Fatalf("first request: unexpected error %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call after first failure, got %d", calls)
+	}
+
+	if _, err := cb.RoundTrip(req); err != ErrCircuitOpen {
+		t.Fatalf("expected breaker to be open after a single failure, got err=%v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("base RoundTrip must not be called while circuit is open, got %d calls", calls)
+	}
+}
+
+func TestCircuitBreakerRequiresThresholdFailuresWithinWindow(t *testing.T) {
+	calls := 0
+	cb := newCircuitBreaker(
+		CircuitBreakerOptions{FailureThreshold: 3, RollingWindow: time.Second, OpenTimeout: time.Minute},
+		fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+			calls++
+			return &http.Response{StatusCode: 500}, nil
+		}},
+	)
+
+	req := mustReq(t, "example.com")
+	for i := 0; i < 2; i++ {
+		if _, err := cb.RoundTrip(req); err != nil {
+			t.Fatalf("request %d: unexpected error %v", i, err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls before threshold is reached, got %d", calls)
+	}
+
+	if _, err := cb.RoundTrip(req); err != nil {
+		t.Fatalf("third failure should trip the breaker but still reach base, got err=%v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+
+	if _, err := cb.RoundTrip(req); err != ErrCircuitOpen {
+		t.Fatalf("expected breaker to be open after reaching threshold, got err=%v", err)
+	}
+}
+
+func TestCircuitBreakerHalfOpenRecoversOnSuccess(t *testing.T) {
+	fail := true
+	cb := newCircuitBreaker(
+		CircuitBreakerOptions{FailureThreshold: 1, RollingWindow: time.Second, OpenTimeout: 10 * time.Millisecond, MaxOpenTimeout: time.Second},
+		fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+			if fail {
+				return &http.Response{StatusCode: 500}, nil
+			}
+			return &http.Response{StatusCode: 200}, nil
+		}},
+	)
+
+	req := mustReq(t, "example.com")
+	if _, err := cb.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cb.RoundTrip(req); err != ErrCircuitOpen {
+		t.Fatalf("expected open, got %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	fail = false
+
+	resp, err := cb.RoundTrip(req)
+	if err != nil || resp.StatusCode != 200 {
+		t.Fatalf("expected half-open probe to succeed, got resp=%v err=%v", resp, err)
+	}
+
+	state, ok := cb.hosts.Load(req.URL.Host)
+	if !ok {
+		t.Fatal("expected breaker state to be tracked for host")
+	}
+	if got := circuitState(state.(*hostBreaker).state); got != circuitClosed {
+		t.Fatalf("expected breaker to close after successful probe, got state=%d", got)
+	}
+}
+
+func TestFindCircuitBreakerUnwrapsArbitraryChain(t *testing.T) {
+	cb := newCircuitBreaker(CircuitBreakerOptions{}, fakeRoundTripper{fn: func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 200}, nil
+	}})
+
+	chain := &middlewareTransport{base: &loggingTransport{next: &timeoutTransport{next: cb, timeout: time.Second}}}
+
+	found, ok := findCircuitBreaker(chain)
+	if !ok || found != cb {
+		t.Fatalf("expected to unwrap to the circuit breaker transport, got %v ok=%v", found, ok)
+	}
+}
@@ -29,10 +29,17 @@ func New(options ...Option) *Client {
 	if transport == nil {
 		transport = defaultTransport()
 	}
+	// GetProxy 优先于 ProxyURL/ProxyChain：设置了 GetProxy 时不再写入静态代理
+	if opts.GetProxy == nil {
+		applyStaticProxy(transport, opts)
+	}
+
+	var base http.RoundTripper = transport
+	base = wireProxy(base, transport, opts)
 
 	httpClient := &http.Client{
 		Timeout:   opts.Timeout,
-		Transport: buildMiddlewareChain(transport, opts),
+		Transport: buildMiddlewareChain(base, opts),
 	}
 
 	return &Client{
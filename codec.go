@@ -0,0 +1,131 @@
+package httpx
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Codec 请求/响应体的编解码器，用于在 JSON 之外支持其他数据格式
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+	ContentType() string
+}
+
+// jsonCodec 默认编解码器，行为与标准库 encoding/json 一致
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (jsonCodec) ContentType() string                { return "application/json" }
+
+// xmlCodec 基于 encoding/xml 的编解码器
+type xmlCodec struct{}
+
+func (xmlCodec) Marshal(v any) ([]byte, error)      { return xml.Marshal(v) }
+func (xmlCodec) Unmarshal(data []byte, v any) error { return xml.Unmarshal(data, v) }
+func (xmlCodec) ContentType() string                { return "application/xml" }
+
+// formCodec 将结构体编码为 application/x-www-form-urlencoded，字段通过 `form:"..."` tag 命名
+type formCodec struct{}
+
+func (formCodec) Marshal(v any) ([]byte, error) {
+	values, err := structToURLValues(v)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(values.Encode()), nil
+}
+
+func (formCodec) Unmarshal(data []byte, v any) error {
+	return fmt.Errorf("httpx: form codec does not support decoding responses")
+}
+
+func (formCodec) ContentType() string { return "application/x-www-form-urlencoded" }
+
+// structToURLValues 通过反射读取 `form` tag，将结构体（或其指针）转换为 url.Values；
+// url.Values 原样返回，其他 map 类型返回错误
+func structToURLValues(v any) (url.Values, error) {
+	if m, ok := v.(url.Values); ok {
+		return m, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("httpx: form codec requires a struct, got %s", rv.Kind())
+	}
+
+	values := url.Values{}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("form")
+		if tag == "-" {
+			continue
+		}
+		name := field.Name
+		if tag != "" {
+			name = strings.Split(tag, ",")[0]
+		}
+		values.Set(name, fmt.Sprint(rv.Field(i).Interface()))
+	}
+	return values, nil
+}
+
+var (
+	codecRegistry = map[string]Codec{
+		"json": jsonCodec{},
+		"xml":  xmlCodec{},
+		"form": formCodec{},
+	}
+	codecRegistryMu sync.RWMutex
+)
+
+// RegisterCodec 注册一个新的编解码器（如 protobuf、msgpack），name 用于 BodyAs/IntoAs 引用
+func RegisterCodec(name string, c Codec) {
+	codecRegistryMu.Lock()
+	defer codecRegistryMu.Unlock()
+	codecRegistry[name] = c
+}
+
+func getCodec(name string) (Codec, bool) {
+	codecRegistryMu.RLock()
+	defer codecRegistryMu.RUnlock()
+	c, ok := codecRegistry[name]
+	return c, ok
+}
+
+// codecForContentType 按 Content-Type 在已注册的编解码器中做内容协商，忽略参数（如 charset）
+func codecForContentType(contentType string) (Codec, bool) {
+	mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+
+	codecRegistryMu.RLock()
+	defer codecRegistryMu.RUnlock()
+
+	// 确保查找顺序稳定
+	names := make([]string, 0, len(codecRegistry))
+	for name := range codecRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		c := codecRegistry[name]
+		if strings.EqualFold(c.ContentType(), mediaType) {
+			return c, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,172 @@
+package httpx
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type codecTestPayload struct {
+	Name string `json:"name" form:"name"`
+	Age  int    `json:"age" form:"age"`
+}
+
+func TestJSONCodecRoundTrip(t *testing.T) {
+	c := jsonCodec{}
+	in := codecTestPayload{Name: "ada", Age: 30}
+
+	data, err := c.Marshal(in)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var out codecTestPayload
+	if err := c.Unmarshal(data, &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if out != in {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", out, in)
+	}
+	if c.ContentType() != "application/json" {
+		t.Fatalf("unexpected content type: %s", c.ContentType())
+	}
+}
+
+func TestXMLCodecRoundTrip(t *testing.T) {
+	type xmlPayload struct {
+		XMLName xml.Name `xml:"payload"`
+		Name    string   `xml:"name"`
+	}
+
+	c := xmlCodec{}
+	in := xmlPayload{Name: "grace"}
+
+	data, err := c.Marshal(in)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var out xmlPayload
+	if err := c.Unmarshal(data, &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if out.Name != in.Name {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", out, in)
+	}
+	if c.ContentType() != "application/xml" {
+		t.Fatalf("unexpected content type: %s", c.ContentType())
+	}
+}
+
+func TestFormCodecMarshalsStructTags(t *testing.T) {
+	c := formCodec{}
+	in := codecTestPayload{Name: "linus", Age: 55}
+
+	data, err := c.Marshal(in)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if got, want := string(data), "age=55&name=linus"; got != want {
+		t.Fatalf("encoded form mismatch: got %q, want %q", got, want)
+	}
+
+	if err := c.Unmarshal(data, &codecTestPayload{}); err == nil {
+		t.Fatal("expected form codec to reject decoding")
+	}
+}
+
+func TestRegisterCodecAndGetCodec(t *testing.T) {
+	if _, ok := getCodec("custom-test-codec"); ok {
+		t.Fatal("codec should not be registered yet")
+	}
+
+	RegisterCodec("custom-test-codec", jsonCodec{})
+	defer func() {
+		codecRegistryMu.Lock()
+		delete(codecRegistry, "custom-test-codec")
+		codecRegistryMu.Unlock()
+	}()
+
+	c, ok := getCodec("custom-test-codec")
+	if !ok {
+		t.Fatal("expected registered codec to be found")
+	}
+	if c.ContentType() != "application/json" {
+		t.Fatalf("unexpected content type: %s", c.ContentType())
+	}
+}
+
+func TestCodecForContentTypeNegotiation(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		wantOK      bool
+		wantType    string
+	}{
+		{"json with charset", "application/json; charset=utf-8", true, "application/json"},
+		{"xml exact", "application/xml", true, "application/xml"},
+		{"form exact", "application/x-www-form-urlencoded", true, "application/x-www-form-urlencoded"},
+		{"unknown", "application/protobuf", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, ok := codecForContentType(tt.contentType)
+			if ok != tt.wantOK {
+				t.Fatalf("codecForContentType(%q): ok=%v, want %v", tt.contentType, ok, tt.wantOK)
+			}
+			if ok && c.ContentType() != tt.wantType {
+				t.Fatalf("got content type %s, want %s", c.ContentType(), tt.wantType)
+			}
+		})
+	}
+}
+
+func newResponseHandler(statusCode int, contentType, body string) *ResponseHandler {
+	header := make(http.Header)
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return &ResponseHandler{
+		resp: &http.Response{
+			StatusCode: statusCode,
+			Status:     http.StatusText(statusCode),
+			Header:     header,
+			Body:       io.NopCloser(strings.NewReader(body)),
+		},
+	}
+}
+
+func TestIntoAsDecodesWithRegisteredCodec(t *testing.T) {
+	rh := newResponseHandler(200, "application/json", `{"name":"turing","age":41}`)
+
+	var out codecTestPayload
+	if err := rh.IntoAs("json", &out); err != nil {
+		t.Fatalf("IntoAs: %v", err)
+	}
+	if out.Name != "turing" || out.Age != 41 {
+		t.Fatalf("unexpected decode result: %+v", out)
+	}
+}
+
+func TestDecodeNegotiatesContentTypeAndFallsBackToJSON(t *testing.T) {
+	rh := newResponseHandler(200, "application/json", `{"name":"ada","age":30}`)
+	var out codecTestPayload
+	if err := rh.Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if out.Name != "ada" || out.Age != 30 {
+		t.Fatalf("unexpected decode result: %+v", out)
+	}
+
+	rh2 := newResponseHandler(200, "application/vnd.unknown+stuff", `{"name":"grace","age":25}`)
+	var out2 codecTestPayload
+	if err := rh2.Decode(&out2); err != nil {
+		t.Fatalf("Decode with unknown content type should fall back to JSON: %v", err)
+	}
+	if out2.Name != "grace" || out2.Age != 25 {
+		t.Fatalf("unexpected fallback decode result: %+v", out2)
+	}
+}
@@ -46,3 +46,8 @@ func IsTimeout(err error) bool {
 	var t timeout
 	return errors.As(err, &t) && t.Timeout()
 }
+
+// IsCircuitOpen 判断错误是否因熔断器处于打开状态而产生
+func IsCircuitOpen(err error) bool {
+	return errors.Is(err, ErrCircuitOpen)
+}
@@ -0,0 +1,293 @@
+package httpx
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefBuckets 默认延迟直方图桶边界（秒），与 Prometheus client_golang 的 DefBuckets 保持一致
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// MetricsOptions 配置 NewMetricsMiddleware 采集的指标
+type MetricsOptions struct {
+	// Buckets 延迟直方图的桶边界，为空时使用 DefBuckets
+	Buckets []float64
+	// Trace 是否通过 httptrace.ClientTrace 采集 DNS/TLS/TTFB 耗时
+	Trace bool
+}
+
+// Desc 描述一个指标的名称、帮助文本与标签名，形状对齐 prometheus.Desc，
+// 便于调用方写一个薄适配层把 Collect 产出的 Metric 转换为真正的 prometheus.Metric 并注册到自己的 Registry。
+type Desc struct {
+	Name   string
+	Help   string
+	Labels []string
+}
+
+// Metric 是一次指标采样；Buckets/Sum/Count 仅在 Desc 对应直方图时有效
+type Metric struct {
+	Desc      *Desc
+	LabelVals []string
+	Value     float64
+	Buckets   map[float64]uint64
+	Sum       float64
+	Count     uint64
+}
+
+// Collector 形状对齐 prometheus.Collector（Describe/Collect），
+// 让本模块不必直接依赖 github.com/prometheus/client_golang 就能暴露指标。
+type Collector interface {
+	Describe(ch chan<- *Desc)
+	Collect(ch chan<- Metric)
+}
+
+var (
+	requestsDesc = &Desc{Name: "http_client_requests_total", Help: "HTTP client 请求总数", Labels: []string{"service", "method", "host", "status_class"}}
+	latencyDesc  = &Desc{Name: "http_client_request_duration_seconds", Help: "HTTP client 请求延迟", Labels: []string{"service", "method", "host", "status_class"}}
+	retriesDesc  = &Desc{Name: "http_client_retries_total", Help: "HTTP client 重试次数", Labels: []string{"service", "method", "host"}}
+	inFlightDesc = &Desc{Name: "http_client_in_flight_requests", Help: "HTTP client 当前在途请求数", Labels: []string{"service", "host"}}
+	traceDesc    = &Desc{Name: "http_client_trace_seconds", Help: "DNS/TLS/TTFB 阶段耗时", Labels: []string{"service", "host", "stage"}}
+)
+
+// metricsCollector 持有所有标签组合下的计数器/直方图状态，key 为用 "|" 拼接的标签值
+type metricsCollector struct {
+	serviceName string
+	buckets     []float64
+
+	requests sync.Map // key -> *uint64
+	retries  sync.Map // key -> *uint64
+	inFlight sync.Map // key -> *int64
+	latency  sync.Map // key -> *histogramState
+	trace    sync.Map // key -> *histogramState
+}
+
+func (mc *metricsCollector) Describe(ch chan<- *Desc) {
+	ch <- requestsDesc
+	ch <- latencyDesc
+	ch <- retriesDesc
+	ch <- inFlightDesc
+	ch <- traceDesc
+}
+
+func (mc *metricsCollector) Collect(ch chan<- Metric) {
+	mc.requests.Range(func(key, value any) bool {
+		ch <- Metric{
+			Desc:      requestsDesc,
+			LabelVals: append([]string{mc.serviceName}, splitKey(key.(string))...),
+			Value:     float64(atomic.LoadUint64(value.(*uint64))),
+		}
+		return true
+	})
+	mc.retries.Range(func(key, value any) bool {
+		ch <- Metric{
+			Desc:      retriesDesc,
+			LabelVals: append([]string{mc.serviceName}, splitKey(key.(string))...),
+			Value:     float64(atomic.LoadUint64(value.(*uint64))),
+		}
+		return true
+	})
+	mc.inFlight.Range(func(key, value any) bool {
+		ch <- Metric{
+			Desc:      inFlightDesc,
+			LabelVals: append([]string{mc.serviceName}, splitKey(key.(string))...),
+			Value:     float64(atomic.LoadInt64(value.(*int64))),
+		}
+		return true
+	})
+	mc.latency.Range(func(key, value any) bool {
+		buckets, sum, count := value.(*histogramState).snapshot()
+		ch <- Metric{
+			Desc:      latencyDesc,
+			LabelVals: append([]string{mc.serviceName}, splitKey(key.(string))...),
+			Buckets:   buckets,
+			Sum:       sum,
+			Count:     count,
+		}
+		return true
+	})
+	mc.trace.Range(func(key, value any) bool {
+		buckets, sum, count := value.(*histogramState).snapshot()
+		ch <- Metric{
+			Desc:      traceDesc,
+			LabelVals: append([]string{mc.serviceName}, splitKey(key.(string))...),
+			Buckets:   buckets,
+			Sum:       sum,
+			Count:     count,
+		}
+		return true
+	})
+}
+
+func joinKey(parts ...string) string { return strings.Join(parts, "|") }
+func splitKey(key string) []string   { return strings.Split(key, "|") }
+
+func (mc *metricsCollector) countRequest(method, host, statusClass string) {
+	key := joinKey(method, host, statusClass)
+	v, _ := mc.requests.LoadOrStore(key, new(uint64))
+	atomic.AddUint64(v.(*uint64), 1)
+}
+
+func (mc *metricsCollector) countRetry(method, host string) {
+	key := joinKey(method, host)
+	v, _ := mc.retries.LoadOrStore(key, new(uint64))
+	atomic.AddUint64(v.(*uint64), 1)
+}
+
+func (mc *metricsCollector) addInFlight(host string, delta int64) {
+	v, _ := mc.inFlight.LoadOrStore(host, new(int64))
+	atomic.AddInt64(v.(*int64), delta)
+}
+
+func (mc *metricsCollector) observeLatency(method, host, statusClass string, seconds float64) {
+	key := joinKey(method, host, statusClass)
+	v, _ := mc.latency.LoadOrStore(key, newHistogramState(mc.buckets))
+	v.(*histogramState).observe(seconds)
+}
+
+func (mc *metricsCollector) observeTrace(host, stage string, seconds float64) {
+	key := joinKey(host, stage)
+	v, _ := mc.trace.LoadOrStore(key, newHistogramState(mc.buckets))
+	v.(*histogramState).observe(seconds)
+}
+
+// histogramState 累计直方图观测值，counts[i] 统计 <= buckets[i] 的样本数（不含 +Inf 桶）
+type histogramState struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func newHistogramState(buckets []float64) *histogramState {
+	return &histogramState{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogramState) observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sum += v
+	h.count++
+	for i, b := range h.buckets {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+}
+
+func (h *histogramState) snapshot() (buckets map[float64]uint64, sum float64, count uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	buckets = make(map[float64]uint64, len(h.buckets))
+	for i, b := range h.buckets {
+		buckets[b] = h.counts[i]
+	}
+	return buckets, h.sum, h.count
+}
+
+// statusClassOf 将响应归类为 2xx/3xx/4xx/5xx/err
+func statusClassOf(resp *http.Response, err error) string {
+	if err != nil || resp == nil {
+		return "err"
+	}
+	switch resp.StatusCode / 100 {
+	case 2:
+		return "2xx"
+	case 3:
+		return "3xx"
+	case 4:
+		return "4xx"
+	case 5:
+		return "5xx"
+	default:
+		return "err"
+	}
+}
+
+// withHTTPTrace 挂载 httptrace.ClientTrace，采集 DNS/TLS/TTFB 阶段耗时写入 mc
+func withHTTPTrace(ctx context.Context, mc *metricsCollector, host string) context.Context {
+	var dnsStart, tlsStart, connStart time.Time
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			if !dnsStart.IsZero() {
+				mc.observeTrace(host, "dns", time.Since(dnsStart).Seconds())
+			}
+		},
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone: func(_ tls.ConnectionState, _ error) {
+			if !tlsStart.IsZero() {
+				mc.observeTrace(host, "tls", time.Since(tlsStart).Seconds())
+			}
+		},
+		GetConn: func(string) { connStart = time.Now() },
+		GotFirstResponseByte: func() {
+			if !connStart.IsZero() {
+				mc.observeTrace(host, "ttfb", time.Since(connStart).Seconds())
+			}
+		},
+	}
+	return httptrace.WithClientTrace(ctx, trace)
+}
+
+// metricsTransport 指标传输层
+type metricsTransport struct {
+	next http.RoundTripper
+	mc   *metricsCollector
+	opts MetricsOptions
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	method := req.Method
+
+	t.mc.addInFlight(host, 1)
+	defer t.mc.addInFlight(host, -1)
+
+	ctx := req.Context()
+	if t.opts.Trace {
+		ctx = withHTTPTrace(ctx, t.mc, host)
+	}
+	ctx = withRetryHook(ctx, func(attempt int, resp *http.Response, err error) {
+		if attempt > 0 {
+			t.mc.countRetry(method, host)
+		}
+	})
+	req = req.WithContext(ctx)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start).Seconds()
+
+	statusClass := statusClassOf(resp, err)
+	t.mc.countRequest(method, host, statusClass)
+	t.mc.observeLatency(method, host, statusClass, duration)
+
+	return resp, err
+}
+
+func (t *metricsTransport) Unwrap() http.RoundTripper {
+	return t.next
+}
+
+// NewMetricsMiddleware 构建一个采集请求量/延迟/在途请求数/重试次数（以及可选的 DNS/TLS/TTFB 耗时）的中间件，
+// 返回的 Collector 形状对齐 prometheus.Collector，调用方可将其适配并注册到自己的 Prometheus Registry。
+func NewMetricsMiddleware(serviceName string, opts MetricsOptions) (Middleware, Collector) {
+	buckets := opts.Buckets
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	mc := &metricsCollector{serviceName: serviceName, buckets: buckets}
+
+	mw := func(next http.RoundTripper) http.RoundTripper {
+		return &metricsTransport{next: next, mc: mc, opts: opts}
+	}
+
+	return mw, mc
+}
@@ -0,0 +1,142 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestStatusClassOf(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *http.Response
+		err  error
+		want string
+	}{
+		{"2xx", &http.Response{StatusCode: 204}, nil, "2xx"},
+		{"3xx", &http.Response{StatusCode: 301}, nil, "3xx"},
+		{"4xx", &http.Response{StatusCode: 404}, nil, "4xx"},
+		{"5xx", &http.Response{StatusCode: 503}, nil, "5xx"},
+		{"transport error", nil, errors.New("boom"), "err"},
+		{"nil response no error", nil, nil, "err"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statusClassOf(tt.resp, tt.err); got != tt.want {
+				t.Fatalf("statusClassOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistogramStateObserveAndSnapshot(t *testing.T) {
+	h := newHistogramState([]float64{0.1, 0.5, 1})
+
+	h.observe(0.05)
+	h.observe(0.3)
+	h.observe(2)
+
+	buckets, sum, count := h.snapshot()
+	if count != 3 {
+		t.Fatalf("expected count 3, got %d", count)
+	}
+	if sum != 0.05+0.3+2 {
+		t.Fatalf("unexpected sum: %v", sum)
+	}
+	if buckets[0.1] != 1 {
+		t.Fatalf("expected 1 sample <= 0.1, got %d", buckets[0.1])
+	}
+	if buckets[0.5] != 2 {
+		t.Fatalf("expected 2 samples <= 0.5, got %d", buckets[0.5])
+	}
+	if buckets[1] != 2 {
+		t.Fatalf("expected 2 samples <= 1, got %d", buckets[1])
+	}
+}
+
+func TestMetricsCollectorCountersAccumulate(t *testing.T) {
+	mc := &metricsCollector{serviceName: "svc", buckets: DefBuckets}
+
+	mc.countRequest(http.MethodGet, "example.com", "2xx")
+	mc.countRequest(http.MethodGet, "example.com", "2xx")
+	mc.countRetry(http.MethodGet, "example.com")
+	mc.addInFlight("example.com", 1)
+	mc.addInFlight("example.com", 1)
+	mc.addInFlight("example.com", -1)
+	mc.observeLatency(http.MethodGet, "example.com", "2xx", 0.2)
+
+	var metrics []Metric
+	ch := make(chan Metric, 16)
+	go func() {
+		mc.Collect(ch)
+		close(ch)
+	}()
+	for m := range ch {
+		metrics = append(metrics, m)
+	}
+
+	var sawRequests, sawRetries, sawInFlight, sawLatency bool
+	for _, m := range metrics {
+		switch m.Desc {
+		case requestsDesc:
+			sawRequests = true
+			if m.Value != 2 {
+				t.Fatalf("expected 2 requests counted, got %v", m.Value)
+			}
+		case retriesDesc:
+			sawRetries = true
+			if m.Value != 1 {
+				t.Fatalf("expected 1 retry counted, got %v", m.Value)
+			}
+		case inFlightDesc:
+			sawInFlight = true
+			if m.Value != 1 {
+				t.Fatalf("expected in-flight gauge of 1, got %v", m.Value)
+			}
+		case latencyDesc:
+			sawLatency = true
+			if m.Count != 1 {
+				t.Fatalf("expected 1 latency observation, got %d", m.Count)
+			}
+		}
+	}
+	if !sawRequests || !sawRetries || !sawInFlight || !sawLatency {
+		t.Fatalf("missing expected metric kinds: requests=%v retries=%v inFlight=%v latency=%v",
+			sawRequests, sawRetries, sawInFlight, sawLatency)
+	}
+}
+
+func TestMetricsTransportRecordsRequestAndLatency(t *testing.T) {
+	mw, collector := NewMetricsMiddleware("svc", MetricsOptions{})
+	base := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+	})
+	rt := mw(base)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found, foundLatency := false, false
+	ch := make(chan Metric, 16)
+	go func() {
+		collector.Collect(ch)
+		close(ch)
+	}()
+	for m := range ch {
+		if m.Desc == requestsDesc && m.Value == 1 {
+			found = true
+		}
+		if m.Desc == latencyDesc && m.Count == 1 {
+			foundLatency = true
+		}
+	}
+	if !found {
+		t.Fatal("expected metrics transport to record exactly one request")
+	}
+	if !foundLatency {
+		t.Fatal("expected metrics transport to record exactly one latency observation")
+	}
+}
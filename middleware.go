@@ -2,7 +2,6 @@ package httpx
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"net/http"
 	"time"
@@ -15,6 +14,12 @@ func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
 	return f(req)
 }
 
+// roundTripperUnwrapper 由包装了下一层 http.RoundTripper 的传输层实现，
+// 让 CircuitState 等观测性代码可以沿中间件链向内查找，而不必为每种中间件单独硬编码类型。
+type roundTripperUnwrapper interface {
+	Unwrap() http.RoundTripper
+}
+
 // middlewareTransport 中间件传输层
 type middlewareTransport struct {
 	base http.RoundTripper
@@ -24,6 +29,10 @@ func (mt *middlewareTransport) RoundTrip(req *http.Request) (*http.Response, err
 	return mt.base.RoundTrip(req)
 }
 
+func (mt *middlewareTransport) Unwrap() http.RoundTripper {
+	return mt.base
+}
+
 // buildMiddlewareChain 构建中间件链
 func buildMiddlewareChain(base http.RoundTripper, opts *Options) http.RoundTripper {
 	// 从后往前包装
@@ -52,62 +61,61 @@ func buildMiddlewareChain(base http.RoundTripper, opts *Options) http.RoundTripp
 	return &middlewareTransport{base: rt}
 }
 
-// LoggingMiddleware 日志中间件
-func LoggingMiddleware() Middleware {
-	return func(next http.RoundTripper) http.RoundTripper {
-		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
-			start := time.Now()
+// loggingTransport 日志传输层
+type loggingTransport struct {
+	next http.RoundTripper
+}
 
-			log.Printf("[HTTP] --> %s %s", req.Method, req.URL.String())
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
 
-			resp, err := next.RoundTrip(req)
+	log.Printf("[HTTP] --> %s %s", req.Method, req.URL.String())
 
-			duration := time.Since(start)
-			if err != nil {
-				log.Printf("[HTTP] <-- %s %s | ERROR: %v | %dms",
-					req.Method, req.URL.String(), err, duration.Milliseconds())
-			} else {
-				log.Printf("[HTTP] <-- %s %s | %d | %dms",
-					req.Method, req.URL.String(), resp.StatusCode, duration.Milliseconds())
-			}
+	resp, err := t.next.RoundTrip(req)
 
-			return resp, err
-		})
+	duration := time.Since(start)
+	if err != nil {
+		log.Printf("[HTTP] <-- %s %s | ERROR: %v | %dms",
+			req.Method, req.URL.String(), err, duration.Milliseconds())
+	} else {
+		log.Printf("[HTTP] <-- %s %s | %d | %dms",
+			req.Method, req.URL.String(), resp.StatusCode, duration.Milliseconds())
 	}
+
+	return resp, err
+}
+
+func (t *loggingTransport) Unwrap() http.RoundTripper {
+	return t.next
 }
 
-// MetricsMiddleware Prometheus 指标中间件（示例）
-func MetricsMiddleware(serviceName string) Middleware {
+// LoggingMiddleware 日志中间件
+func LoggingMiddleware() Middleware {
 	return func(next http.RoundTripper) http.RoundTripper {
-		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
-			start := time.Now()
-			resp, err := next.RoundTrip(req)
-			duration := time.Since(start)
-
-			// 这里可以集成 Prometheus
-			status := "error"
-			if resp != nil {
-				status = fmt.Sprintf("%d", resp.StatusCode)
-			}
-
-			// prometheus.RecordHTTPRequest(serviceName, req.Method, status, duration)
-			_ = status
-			_ = duration
-
-			return resp, err
-		})
+		return &loggingTransport{next: next}
 	}
 }
 
+// timeoutTransport 请求级超时传输层
+type timeoutTransport struct {
+	next    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	defer cancel()
+
+	return t.next.RoundTrip(req.WithContext(ctx))
+}
+
+func (t *timeoutTransport) Unwrap() http.RoundTripper {
+	return t.next
+}
+
 // TimeoutMiddleware 请求级超时中间件
 func TimeoutMiddleware(timeout time.Duration) Middleware {
 	return func(next http.RoundTripper) http.RoundTripper {
-		return RoundTripFunc(func(req *http.Request) (*http.Response, error) {
-			ctx := req.Context()
-			ctx, cancel := context.WithTimeout(ctx, timeout)
-			defer cancel()
-
-			return next.RoundTrip(req.WithContext(ctx))
-		})
+		return &timeoutTransport{next: next, timeout: timeout}
 	}
 }
@@ -14,6 +14,15 @@ type Options struct {
 	Headers         http.Header
 	Middlewares     []Middleware
 	Transport       *http.Transport
+
+	// ProxyURL 单个代理地址，支持 http(s)://、socks5:// 和 socks5h:// scheme
+	ProxyURL string
+	// ProxyChain 多级代理链，按顺序逐跳隧道转发
+	ProxyChain []string
+	// GetProxy 按请求动态选择代理地址，优先级高于 ProxyURL/ProxyChain
+	GetProxy GetProxyFunc
+	// ProxyBypass 命中这些 host 的请求不走代理（NO_PROXY 语义）
+	ProxyBypass []string
 }
 
 type Option func(*Options)
@@ -79,3 +88,52 @@ func WithTransport(t *http.Transport) Option {
 		o.Transport = t
 	}
 }
+
+// WithProxy 设置单个代理地址，支持 http(s):// 和 socks5(h):// scheme，URL 中可携带 basic auth
+func WithProxy(proxyURL string) Option {
+	return func(o *Options) {
+		o.ProxyURL = proxyURL
+	}
+}
+
+// WithProxyChain 设置多级代理链，请求依次通过每一跳代理隧道转发到下一跳，最后一跳连接真实目标
+func WithProxyChain(proxyURLs ...string) Option {
+	return func(o *Options) {
+		o.ProxyChain = proxyURLs
+	}
+}
+
+// WithGetProxy 按请求动态解析代理地址（例如轮换代理池），优先级高于 WithProxy/WithProxyChain
+func WithGetProxy(fn GetProxyFunc) Option {
+	return func(o *Options) {
+		o.GetProxy = fn
+	}
+}
+
+// WithProxyBypass 配置无需走代理的 host（NO_PROXY 语义），支持后缀匹配，如 ".internal.example.com"
+func WithProxyBypass(hosts ...string) Option {
+	return func(o *Options) {
+		o.ProxyBypass = hosts
+	}
+}
+
+// WithRateLimit 配置客户端全局令牌桶限流
+func WithRateLimit(rps float64, burst int) Option {
+	return func(o *Options) {
+		o.Middlewares = append(o.Middlewares, RateLimitMiddleware(RateLimitOptions{RPS: rps, Burst: burst}))
+	}
+}
+
+// WithPerHostRateLimit 配置按 host 独立的令牌桶限流
+func WithPerHostRateLimit(rps float64, burst int) Option {
+	return func(o *Options) {
+		o.Middlewares = append(o.Middlewares, RateLimitMiddleware(RateLimitOptions{PerHostRPS: rps, PerHostBurst: burst}))
+	}
+}
+
+// WithMaxInFlight 限制客户端同时在途的请求数
+func WithMaxInFlight(n int) Option {
+	return func(o *Options) {
+		o.Middlewares = append(o.Middlewares, RateLimitMiddleware(RateLimitOptions{MaxInFlight: n}))
+	}
+}
@@ -0,0 +1,268 @@
+package httpx
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// GetProxyFunc 按请求动态解析代理地址；返回空字符串表示该请求不走代理
+type GetProxyFunc func(ctx context.Context, reqURL *url.URL) (string, error)
+
+// proxyDialContext 根据单个代理地址或一条代理链构建 DialContext，支持 socks5://、socks5h://、http:// 和 https://。
+// bypass 命中的目标 host 会跳过代理直接拨号（NO_PROXY 语义）。
+func proxyDialContext(proxyURLs []string, bypass []string) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	directDialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if len(proxyURLs) == 0 {
+		return directDialer.DialContext, nil
+	}
+
+	for _, raw := range proxyURLs {
+		if _, err := url.Parse(raw); err != nil {
+			return nil, fmt.Errorf("httpx: invalid proxy url %q: %w", raw, err)
+		}
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if isProxyBypassed(addr, bypass) {
+			return directDialer.DialContext(ctx, network, addr)
+		}
+
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+
+		// 依次建立到每一跳代理的连接，再通过上一跳隧道连接下一跳/目标地址
+		var conn net.Conn
+		for i, raw := range proxyURLs {
+			u, _ := url.Parse(raw)
+
+			var next string
+			if i == len(proxyURLs)-1 {
+				next = addr
+			} else {
+				next = hostPort(proxyURLs[i+1])
+			}
+
+			c, err := dialThroughProxy(ctx, conn, dialer, network, u, next)
+			if err != nil {
+				if conn != nil {
+					conn.Close()
+				}
+				return nil, fmt.Errorf("httpx: dial proxy hop %d (%s): %w", i, u.Host, err)
+			}
+			conn = c
+		}
+		return conn, nil
+	}, nil
+}
+
+// dialThroughProxy 通过一跳代理（via==nil 时直接拨号）建立到 target 的连接
+func dialThroughProxy(ctx context.Context, via net.Conn, dialer *net.Dialer, network string, proxyURL *url.URL, target string) (net.Conn, error) {
+	switch proxyURL.Scheme {
+	case "socks5", "socks5h":
+		var auth *proxy.Auth
+		if proxyURL.User != nil {
+			pass, _ := proxyURL.User.Password()
+			auth = &proxy.Auth{User: proxyURL.User.Username(), Password: pass}
+		}
+		baseDialer := baseNetDialer(dialer, via)
+		d, err := proxy.SOCKS5(network, hostPortFromURL(proxyURL), auth, baseDialer)
+		if err != nil {
+			return nil, err
+		}
+		if cd, ok := d.(proxy.ContextDialer); ok {
+			return cd.DialContext(ctx, network, target)
+		}
+		return d.Dial(network, target)
+
+	case "http", "https":
+		conn := via
+		if conn == nil {
+			c, err := dialer.DialContext(ctx, network, hostPortFromURL(proxyURL))
+			if err != nil {
+				return nil, err
+			}
+			conn = c
+		}
+		if proxyURL.Scheme == "https" {
+			// https 代理：先与代理本身完成 TLS 握手，再在其上发起 CONNECT
+			tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				return nil, err
+			}
+			conn = tlsConn
+		}
+		if err := httpConnectTunnel(conn, proxyURL, target); err != nil {
+			return nil, err
+		}
+		return conn, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+	}
+}
+
+// baseNetDialer 适配 golang.org/x/net/proxy 期望的 proxy.Dialer 接口；via 非空时复用已建立的上一跳连接
+func baseNetDialer(dialer *net.Dialer, via net.Conn) proxy.Dialer {
+	if via != nil {
+		return fixedConnDialer{conn: via}
+	}
+	return dialer
+}
+
+// fixedConnDialer 始终返回同一个已建立的连接，用于在代理链中复用上一跳隧道
+type fixedConnDialer struct{ conn net.Conn }
+
+func (f fixedConnDialer) Dial(network, addr string) (net.Conn, error) { return f.conn, nil }
+
+// httpConnectTunnel 在已建立的连接上发起 HTTP CONNECT，建立到 target 的隧道
+func httpConnectTunnel(conn net.Conn, proxyURL *url.URL, target string) error {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: target},
+		Host:   target,
+		Header: make(http.Header),
+	}
+	if proxyURL.User != nil {
+		pass, _ := proxyURL.User.Password()
+		req.SetBasicAuth(proxyURL.User.Username(), pass)
+	}
+	if err := req.Write(conn); err != nil {
+		return err
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("proxy CONNECT failed: %s", resp.Status)
+	}
+	return nil
+}
+
+func hostPortFromURL(u *url.URL) string { return hostPort(u.String()) }
+
+// hostPort 提取代理地址的 host:port，缺省端口按 scheme 补全
+func hostPort(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	if u.Port() != "" {
+		return u.Host
+	}
+	switch u.Scheme {
+	case "https":
+		return u.Host + ":443"
+	case "socks5", "socks5h":
+		return u.Host + ":1080"
+	default:
+		return u.Host + ":80"
+	}
+}
+
+// isProxyBypassed 判断 host 是否命中 ProxyBypass 列表（精确匹配或作为后缀匹配）
+func isProxyBypassed(host string, bypass []string) bool {
+	host = strings.Split(host, ":")[0]
+	for _, b := range bypass {
+		b = strings.TrimPrefix(b, ".")
+		if host == b || strings.HasSuffix(host, "."+b) {
+			return true
+		}
+	}
+	return false
+}
+
+// dynamicProxyTransport 为 WithGetProxy 按请求动态选择代理，通过按代理地址缓存的 Transport 池
+// 正确绕开 http.Transport 固定 DialContext 导致的连接池复用问题
+type dynamicProxyTransport struct {
+	base     http.RoundTripper // GetProxy 返回空字符串（不走代理）时使用
+	template *http.Transport   // 克隆模板，仅替换 DialContext
+	getProxy GetProxyFunc
+	bypass   []string
+	pool     sync.Map // proxyURL string -> *http.Transport
+}
+
+func (dt *dynamicProxyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isProxyBypassed(req.URL.Host, dt.bypass) {
+		return dt.base.RoundTrip(req)
+	}
+
+	proxyURL, err := dt.getProxy(req.Context(), req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("httpx: resolve proxy: %w", err)
+	}
+	if proxyURL == "" {
+		return dt.base.RoundTrip(req)
+	}
+
+	rt, err := dt.transportFor(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	return rt.RoundTrip(req)
+}
+
+// Unwrap 暴露 GetProxy 未命中时使用的下一层传输，供 findCircuitBreaker 等观测性代码穿透中间件链
+func (dt *dynamicProxyTransport) Unwrap() http.RoundTripper {
+	return dt.base
+}
+
+func (dt *dynamicProxyTransport) transportFor(proxyURL string) (http.RoundTripper, error) {
+	if v, ok := dt.pool.Load(proxyURL); ok {
+		return v.(http.RoundTripper), nil
+	}
+
+	dial, err := proxyDialContext([]string{proxyURL}, nil)
+	if err != nil {
+		return nil, err
+	}
+	t := dt.template.Clone()
+	t.Proxy = nil
+	t.DialContext = dial
+
+	actual, _ := dt.pool.LoadOrStore(proxyURL, t)
+	return actual.(http.RoundTripper), nil
+}
+
+// applyStaticProxy 将 ProxyURL/ProxyChain 写入 transport 的 DialContext；ProxyChain 优先于 ProxyURL。
+// 解析失败时保持 transport 不变（按现有连接配置降级）。
+func applyStaticProxy(transport *http.Transport, opts *Options) {
+	urls := opts.ProxyChain
+	if len(urls) == 0 && opts.ProxyURL != "" {
+		urls = []string{opts.ProxyURL}
+	}
+	if len(urls) == 0 {
+		return
+	}
+	dial, err := proxyDialContext(urls, opts.ProxyBypass)
+	if err != nil {
+		return
+	}
+	// 代理已经在 DialContext 里显式处理（含 SOCKS5 和 CONNECT 隧道），避免与内置 Proxy 字段重复生效
+	transport.Proxy = nil
+	transport.DialContext = dial
+}
+
+// wireProxy 在使用 WithGetProxy 时，返回一个按请求动态选择代理、接管 RoundTrip 的传输层；
+// 否则原样返回 base（静态代理已经通过 applyStaticProxy 写入 transport）。
+func wireProxy(base http.RoundTripper, transportTemplate *http.Transport, opts *Options) http.RoundTripper {
+	if opts.GetProxy != nil {
+		return &dynamicProxyTransport{
+			base:     base,
+			template: transportTemplate,
+			getProxy: opts.GetProxy,
+			bypass:   opts.ProxyBypass,
+		}
+	}
+	return base
+}
@@ -0,0 +1,99 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestHostPortDefaultsByScheme(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"http default port", "http://proxy.example.com", "proxy.example.com:80"},
+		{"https default port", "https://proxy.example.com", "proxy.example.com:443"},
+		{"socks5 default port", "socks5://proxy.example.com", "proxy.example.com:1080"},
+		{"explicit port kept", "http://proxy.example.com:8080", "proxy.example.com:8080"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hostPort(tt.raw); got != tt.want {
+				t.Fatalf("hostPort(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsProxyBypassed(t *testing.T) {
+	bypass := []string{"internal.example.com", ".corp.example.com"}
+
+	tests := []struct {
+		name string
+		host string
+		want bool
+	}{
+		{"exact match", "internal.example.com:443", true},
+		{"suffix match", "api.corp.example.com", true},
+		{"no match", "public.example.com", false},
+		{"suffix must be a label boundary", "notcorp.example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isProxyBypassed(tt.host, bypass); got != tt.want {
+				t.Fatalf("isProxyBypassed(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDynamicProxyTransportBypassUsesBaseDirectly(t *testing.T) {
+	called := false
+	base := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+	})
+
+	rt := &dynamicProxyTransport{
+		base:   base,
+		bypass: []string{"example.com"},
+		getProxy: func(_ context.Context, _ *url.URL) (string, error) {
+			return "http://should-not-be-used.invalid", nil
+		},
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Fatal("expected bypassed host to hit base transport directly, skipping GetProxy")
+	}
+}
+
+func TestDynamicProxyTransportEmptyProxyUsesBase(t *testing.T) {
+	called := false
+	base := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+	})
+
+	rt := &dynamicProxyTransport{
+		base: base,
+		getProxy: func(_ context.Context, _ *url.URL) (string, error) {
+			return "", nil
+		},
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Fatal("expected empty proxy resolution to fall through to base transport")
+	}
+}
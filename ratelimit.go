@@ -0,0 +1,191 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitHook 在请求因限流等待后被调用，wait 为实际等待时长
+type RateLimitHook func(req *http.Request, wait time.Duration)
+
+// tokenBucket 最小化令牌桶实现，避免引入 x/time/rate 依赖
+type tokenBucket struct {
+	mu         sync.Mutex
+	rate       float64 // 每秒产生的令牌数
+	burst      float64 // 桶容量
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newTokenBucket(rps float64, burst int) *tokenBucket {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:       rps,
+		burst:      float64(burst),
+		tokens:     float64(burst),
+		lastRefill: time.Now(),
+	}
+}
+
+func (b *tokenBucket) refillLocked(now time.Time) {
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	b.tokens += elapsed * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.lastRefill = now
+}
+
+// take 尝试立即取走一个令牌；失败时返回需要再等待的时长
+func (b *tokenBucket) take() (ok bool, retryAfter time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refillLocked(time.Now())
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	missing := 1 - b.tokens
+	return false, time.Duration(missing / b.rate * float64(time.Second))
+}
+
+// drainFor 在 d 时间内不发放新令牌，用于响应服务端 Retry-After
+func (b *tokenBucket) drainFor(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = 0
+	b.lastRefill = time.Now().Add(d)
+}
+
+// RateLimitOptions 限流中间件配置
+type RateLimitOptions struct {
+	// RPS/Burst 全局令牌桶
+	RPS   float64
+	Burst int
+	// PerHostRPS/PerHostBurst 每个 host 独立的令牌桶，0 表示不启用
+	PerHostRPS   float64
+	PerHostBurst int
+	// MaxInFlight 限制同时在途的请求数，0 表示不限制
+	MaxInFlight int
+	// OnWait 等待令牌期间的观测钩子，可用于指标中间件
+	OnWait RateLimitHook
+}
+
+type rateLimitTransport struct {
+	base     http.RoundTripper
+	opts     RateLimitOptions
+	global   *tokenBucket
+	perHost  sync.Map // string -> *tokenBucket
+	inFlight chan struct{}
+}
+
+// RateLimitMiddleware 基于令牌桶和信号量，对请求进行全局/按 host 限流和并发数限制
+func RateLimitMiddleware(opts RateLimitOptions) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		rt := &rateLimitTransport{base: next, opts: opts}
+		if opts.RPS > 0 {
+			rt.global = newTokenBucket(opts.RPS, opts.Burst)
+		}
+		if opts.MaxInFlight > 0 {
+			rt.inFlight = make(chan struct{}, opts.MaxInFlight)
+		}
+		return rt
+	}
+}
+
+func (rt *rateLimitTransport) hostBucket(host string) *tokenBucket {
+	if v, ok := rt.perHost.Load(host); ok {
+		return v.(*tokenBucket)
+	}
+	b := newTokenBucket(rt.opts.PerHostRPS, rt.opts.PerHostBurst)
+	actual, _ := rt.perHost.LoadOrStore(host, b)
+	return actual.(*tokenBucket)
+}
+
+func (rt *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	var waited time.Duration
+
+	if rt.global != nil {
+		d, err := rt.awaitBucket(ctx, rt.global)
+		if err != nil {
+			return nil, err
+		}
+		waited += d
+	}
+	if rt.opts.PerHostRPS > 0 {
+		d, err := rt.awaitBucket(ctx, rt.hostBucket(req.URL.Host))
+		if err != nil {
+			return nil, err
+		}
+		waited += d
+	}
+	if rt.inFlight != nil {
+		select {
+		case rt.inFlight <- struct{}{}:
+			defer func() { <-rt.inFlight }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if waited > 0 && rt.opts.OnWait != nil {
+		rt.opts.OnWait(req, waited)
+	}
+
+	resp, err := rt.base.RoundTrip(req)
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests && rt.opts.PerHostRPS > 0 {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			rt.hostBucket(req.URL.Host).drainFor(d)
+		}
+	}
+	return resp, err
+}
+
+// Unwrap 暴露被包裹的下一层传输，供 findCircuitBreaker 等观测性代码穿透中间件链
+func (rt *rateLimitTransport) Unwrap() http.RoundTripper {
+	return rt.base
+}
+
+// awaitBucket 等待令牌可用，期间尊重 context 取消
+func (rt *rateLimitTransport) awaitBucket(ctx context.Context, b *tokenBucket) (time.Duration, error) {
+	var waited time.Duration
+	for {
+		ok, retryAfter := b.take()
+		if ok {
+			return waited, nil
+		}
+
+		timer := time.NewTimer(retryAfter)
+		select {
+		case <-timer.C:
+			waited += retryAfter
+		case <-ctx.Done():
+			timer.Stop()
+			return waited, ctx.Err()
+		}
+	}
+}
+
+// parseRetryAfter 解析 Retry-After 头，支持秒数和 HTTP-date 两种格式
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t), true
+	}
+	return 0, false
+}
@@ -0,0 +1,190 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketTakeRespectsBurst(t *testing.T) {
+	b := newTokenBucket(1, 2)
+
+	ok, _ := b.take()
+	if !ok {
+		t.Fatal("expected first take to succeed within burst")
+	}
+	ok, _ = b.take()
+	if !ok {
+		t.Fatal("expected second take to succeed within burst")
+	}
+
+	ok, retryAfter := b.take()
+	if ok {
+		t.Fatal("expected third take to fail once burst is exhausted")
+	}
+	if retryAfter <= 0 {
+		t.Fatalf("expected a positive retry-after, got %v", retryAfter)
+	}
+}
+
+func TestTokenBucketRefillsOverTime(t *testing.T) {
+	b := newTokenBucket(100, 1) // 100 tokens/sec, burst 1
+
+	ok, _ := b.take()
+	if !ok {
+		t.Fatal("expected first take to succeed")
+	}
+	if ok, _ := b.take(); ok {
+		t.Fatal("expected bucket to be empty immediately after draining burst")
+	}
+
+	time.Sleep(15 * time.Millisecond) // >= 1 token at 100/s
+	if ok, _ := b.take(); !ok {
+		t.Fatal("expected bucket to have refilled a token after waiting")
+	}
+}
+
+func TestTokenBucketDrainFor(t *testing.T) {
+	b := newTokenBucket(1000, 5)
+	b.drainFor(20 * time.Millisecond)
+
+	if ok, _ := b.take(); ok {
+		t.Fatal("expected bucket to be drained immediately after drainFor")
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	if ok, _ := b.take(); !ok {
+		t.Fatal("expected bucket to refill again once the drain period has passed")
+	}
+}
+
+func TestRateLimitMiddlewareLimitsMaxInFlight(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+
+	base := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		started <- struct{}{}
+		<-release
+		return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+	})
+
+	mw := RateLimitMiddleware(RateLimitOptions{MaxInFlight: 1})
+	rt := mw(base)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+
+	done := make(chan struct{})
+	go func() {
+		rt.RoundTrip(req)
+		done <- struct{}{}
+	}()
+
+	<-started // first request is now in flight, holding the single slot
+
+	secondDone := make(chan struct{})
+	go func() {
+		rt.RoundTrip(req)
+		secondDone <- struct{}{}
+	}()
+
+	select {
+	case <-started:
+		t.Fatal("second request should not start while MaxInFlight slot is held")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	<-done
+	<-secondDone
+}
+
+func TestRateLimitMiddlewareDrainsHostBucketOnRetryAfter(t *testing.T) {
+	first := true
+	base := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if first {
+			first = false
+			resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: make(http.Header), Body: http.NoBody}
+			resp.Header.Set("Retry-After", "1")
+			return resp, nil
+		}
+		return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+	})
+
+	mw := RateLimitMiddleware(RateLimitOptions{PerHostRPS: 1000, PerHostBurst: 5})
+	rt := mw(base).(*rateLimitTransport)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	bucket := rt.hostBucket("example.com")
+	if ok, _ := bucket.take(); ok {
+		t.Fatal("expected host bucket to be drained after a 429 with Retry-After")
+	}
+}
+
+func TestAwaitBucketRespectsContextCancellation(t *testing.T) {
+	rt := &rateLimitTransport{}
+	b := newTokenBucket(0.001, 1) // effectively never refills within the test window
+	b.take()                     // drain the single token
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := rt.awaitBucket(ctx, b); err != ctx.Err() {
+		t.Fatalf("expected context error, got %v", err)
+	}
+}
+
+func TestRateLimitMiddlewareReturnsFreshTransportPerApplication(t *testing.T) {
+	mw := RateLimitMiddleware(RateLimitOptions{MaxInFlight: 1})
+
+	calledA, calledB := false, false
+	baseA := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calledA = true
+		return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+	})
+	baseB := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calledB = true
+		return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+	})
+
+	rtA := mw(baseA)
+	rtB := mw(baseB)
+
+	if rtA == rtB {
+		t.Fatal("expected each application of the middleware to return a distinct transport instance")
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	if _, err := rtA.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !calledA || calledB {
+		t.Fatalf("expected rtA to call baseA only, got calledA=%v calledB=%v", calledA, calledB)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		ok    bool
+	}{
+		{"empty", "", false},
+		{"seconds", "5", true},
+		{"http-date", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), true},
+		{"garbage", "not-a-value", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := parseRetryAfter(tt.value)
+			if ok != tt.ok {
+				t.Fatalf("parseRetryAfter(%q): ok=%v, want %v", tt.value, ok, tt.ok)
+			}
+		})
+	}
+}
@@ -95,6 +95,33 @@ func (rb *RequestBuilder) JSONBody(v any) *RequestBuilder {
 	return rb
 }
 
+// XMLBody 使用 XML 编码请求体
+func (rb *RequestBuilder) XMLBody(v any) *RequestBuilder {
+	return rb.BodyAs("xml", v)
+}
+
+// FormBody 使用 application/x-www-form-urlencoded 编码请求体，字段名取自 `form:"..."` tag
+func (rb *RequestBuilder) FormBody(v any) *RequestBuilder {
+	return rb.BodyAs("form", v)
+}
+
+// BodyAs 使用指定名称注册的 Codec 编码请求体（见 RegisterCodec）
+func (rb *RequestBuilder) BodyAs(codecName string, v any) *RequestBuilder {
+	codec, ok := getCodec(codecName)
+	if !ok {
+		rb.err = fmt.Errorf("httpx: no codec registered for %q", codecName)
+		return rb
+	}
+	data, err := codec.Marshal(v)
+	if err != nil {
+		rb.err = fmt.Errorf("marshal %s body: %w", codecName, err)
+		return rb
+	}
+	rb.body = bytes.NewReader(data)
+	rb.headers.Set("Content-Type", codec.ContentType())
+	return rb
+}
+
 func (rb *RequestBuilder) Do(ctx context.Context) *ResponseHandler {
 	if rb.err != nil {
 		return &ResponseHandler{err: rb.err}
@@ -138,7 +165,7 @@ func (rb *RequestBuilder) Do(ctx context.Context) *ResponseHandler {
 
 	// 执行请求
 	resp, err := rb.client.httpClient.Do(req)
-	return &ResponseHandler{resp: resp, err: err}
+	return &ResponseHandler{resp: resp, err: err, req: req, client: rb.client}
 }
 
 // 便捷方法
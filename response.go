@@ -8,8 +8,10 @@ import (
 )
 
 type ResponseHandler struct {
-	resp *http.Response
-	err  error
+	resp   *http.Response
+	err    error
+	req    *http.Request
+	client *Client
 }
 
 // Into 解析 JSON 响应
@@ -38,6 +40,52 @@ func (rh *ResponseHandler) Into(v any) error {
 	return nil
 }
 
+// IntoAs 使用指定名称注册的 Codec 解析响应体（见 RegisterCodec）
+func (rh *ResponseHandler) IntoAs(codecName string, v any) error {
+	codec, ok := getCodec(codecName)
+	if !ok {
+		return fmt.Errorf("httpx: no codec registered for %q", codecName)
+	}
+	return rh.decodeWith(codec, v)
+}
+
+// Decode 根据响应 Content-Type 自动选择已注册的 Codec 解析响应体，找不到匹配项时退回 JSON
+func (rh *ResponseHandler) Decode(v any) error {
+	if rh.err != nil {
+		return rh.err
+	}
+	codec, ok := codecForContentType(rh.resp.Header.Get("Content-Type"))
+	if !ok {
+		codec = jsonCodec{}
+	}
+	return rh.decodeWith(codec, v)
+}
+
+func (rh *ResponseHandler) decodeWith(codec Codec, v any) error {
+	if rh.err != nil {
+		return rh.err
+	}
+	defer rh.resp.Body.Close()
+
+	if rh.resp.StatusCode < 200 || rh.resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(rh.resp.Body)
+		return &HTTPError{
+			StatusCode: rh.resp.StatusCode,
+			Status:     rh.resp.Status,
+			Body:       body,
+		}
+	}
+
+	data, err := io.ReadAll(rh.resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response body: %w", err)
+	}
+	if err := codec.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decode %s response: %w", codec.ContentType(), err)
+	}
+	return nil
+}
+
 // Raw 返回原始响应（需要手动关闭 Body）
 func (rh *ResponseHandler) Raw() (*http.Response, error) {
 	return rh.resp, rh.err
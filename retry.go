@@ -1,6 +1,7 @@
 package httpx
 
 import (
+	"context"
 	"math"
 	"math/rand"
 	"net/http"
@@ -9,6 +10,17 @@ import (
 
 type RetryConditionFunc func(resp *http.Response, err error) bool
 
+// retryAttemptHook 在每次请求尝试结束后被调用，attempt 从 0 开始（0 为首次尝试，非重试）
+type retryAttemptHook func(attempt int, resp *http.Response, err error)
+
+type retryHookCtxKey struct{}
+
+// withRetryHook 将重试观测钩子注入 context，供 retryTransport 在每次尝试后调用，
+// 供 MetricsMiddleware 等外层中间件统计每次尝试，而不需要侵入 retryTransport 内部结构
+func withRetryHook(ctx context.Context, hook retryAttemptHook) context.Context {
+	return context.WithValue(ctx, retryHookCtxKey{}, hook)
+}
+
 // DefaultRetryIf 默认重试条件：网络错误 + 5xx + 429
 func DefaultRetryIf(resp *http.Response, err error) bool {
 	if err != nil {
@@ -45,6 +57,10 @@ func (rt *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		// 执行请求
 		resp, err = rt.base.RoundTrip(req)
 
+		if hook, ok := ctx.Value(retryHookCtxKey{}).(retryAttemptHook); ok {
+			hook(attempt, resp, err)
+		}
+
 		// 判断是否需要重试
 		shouldRetry := rt.retryIf(resp, err)
 		if !shouldRetry || attempt == rt.maxRetries {
@@ -71,6 +87,11 @@ func (rt *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	return resp, err
 }
 
+// Unwrap 暴露被包裹的下一层传输，供 findCircuitBreaker 等观测性代码穿透中间件链
+func (rt *retryTransport) Unwrap() http.RoundTripper {
+	return rt.base
+}
+
 // calculateBackoff 计算退避时间（指数退避 + jitter）
 func (rt *retryTransport) calculateBackoff(attempt int) time.Duration {
 	// 指数退避: min * 2^attempt
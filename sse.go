@@ -0,0 +1,167 @@
+package httpx
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event 表示一条 SSE 事件（text/event-stream）
+type Event struct {
+	ID    string
+	Event string
+	Data  string
+	Retry time.Duration
+}
+
+// defaultSSERetry 服务端未指定 retry 字段时的重连间隔
+const defaultSSERetry = 3 * time.Second
+
+// SSE 以流式方式消费 text/event-stream 响应，不会缓冲整个响应体。
+// 连接意外中断时会使用最后一次收到的 event id（Last-Event-ID）和服务端建议的 retry 间隔自动重连。
+func (rh *ResponseHandler) SSE(ctx context.Context, handler func(Event) error) error {
+	if rh.err != nil {
+		return rh.err
+	}
+
+	resp := rh.resp
+	lastEventID := ""
+	retry := defaultSSERetry
+
+	for {
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status, Body: body}
+		}
+
+		var handlerErr error
+		err := readSSEStream(resp.Body, func(ev Event) error {
+			handlerErr = handler(ev)
+			return handlerErr
+		}, &lastEventID, &retry)
+		resp.Body.Close()
+		if err != nil {
+			if err == errSSEStop {
+				return nil
+			}
+			// handler 返回的错误和 ctx 取消直接返回；其余读取错误视为连接中断
+			if handlerErr != nil || ctx.Err() != nil {
+				return err
+			}
+		}
+
+		// 流结束（EOF 或连接中断），按 retry 间隔自动重连
+		select {
+		case <-time.After(rh.clampRetry(retry)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		resp, err = rh.reconnectSSE(ctx, lastEventID)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// errSSEStop 由 handler 返回以提前停止流式读取，不被当作错误传播
+var errSSEStop = fmt.Errorf("httpx: sse handler requested stop")
+
+// ErrStopSSE 供调用方在 handler 中返回，表示正常停止读取（不触发重连）
+var ErrStopSSE = errSSEStop
+
+// clampRetry 将重连间隔限制在客户端已配置的退避范围内
+func (rh *ResponseHandler) clampRetry(retry time.Duration) time.Duration {
+	if rh.client == nil {
+		return retry
+	}
+	if max := rh.client.opts.RetryMaxBackoff; max > 0 && retry > max {
+		return max
+	}
+	if min := rh.client.opts.RetryMinBackoff; min > 0 && retry < min {
+		return min
+	}
+	return retry
+}
+
+func (rh *ResponseHandler) reconnectSSE(ctx context.Context, lastEventID string) (*http.Response, error) {
+	if rh.req == nil || rh.client == nil {
+		return nil, fmt.Errorf("httpx: sse stream cannot reconnect without original request")
+	}
+	req := rh.req.Clone(ctx)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	return rh.client.httpClient.Do(req)
+}
+
+// readSSEStream 逐行解析 text/event-stream 的 data:/event:/id:/retry: 字段，空行触发分发；
+// 流结束时未被空行终止的最后一个事件同样会分发
+func readSSEStream(body io.Reader, handler func(Event) error, lastEventID *string, retry *time.Duration) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var ev Event
+	var data strings.Builder
+	haveData := false
+
+	dispatch := func() error {
+		if !haveData && ev.Event == "" && ev.ID == "" {
+			return nil
+		}
+		ev.Data = strings.TrimSuffix(data.String(), "\n")
+		if err := handler(ev); err != nil {
+			return err
+		}
+		ev = Event{}
+		data.Reset()
+		haveData = false
+		return nil
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "" {
+			if err := dispatch(); err != nil {
+				return err
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // 注释行
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			haveData = true
+		case "event":
+			ev.Event = value
+		case "id":
+			ev.ID = value
+			*lastEventID = value
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil {
+				*retry = time.Duration(ms) * time.Millisecond
+				ev.Retry = *retry
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	// 流结束前 flush 最后一个未被空行终止的事件
+	return dispatch()
+}
@@ -0,0 +1,113 @@
+package httpx
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadSSEStreamParsesFields(t *testing.T) {
+	stream := "id: 1\n" +
+		"event: greeting\n" +
+		"data: hello\n" +
+		"data: world\n" +
+		"retry: 2500\n" +
+		"\n" +
+		"data: second event\n" +
+		"\n"
+
+	var events []Event
+	lastEventID := ""
+	retry := defaultSSERetry
+
+	err := readSSEStream(strings.NewReader(stream), func(ev Event) error {
+		events = append(events, ev)
+		return nil
+	}, &lastEventID, &retry)
+	if err != nil {
+		t.Fatalf("readSSEStream: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	first := events[0]
+	if first.ID != "1" || first.Event != "greeting" || first.Data != "hello\nworld" {
+		t.Fatalf("unexpected first event: %+v", first)
+	}
+	if first.Retry != 2500*time.Millisecond {
+		t.Fatalf("unexpected retry on first event: %v", first.Retry)
+	}
+
+	second := events[1]
+	if second.Data != "second event" {
+		t.Fatalf("unexpected second event: %+v", second)
+	}
+
+	if lastEventID != "1" {
+		t.Fatalf("expected lastEventID to be updated to %q, got %q", "1", lastEventID)
+	}
+	if retry != 2500*time.Millisecond {
+		t.Fatalf("expected retry interval to be updated, got %v", retry)
+	}
+}
+
+func TestReadSSEStreamFlushesTrailingEventWithoutBlankLine(t *testing.T) {
+	stream := "data: unterminated\n"
+
+	var events []Event
+	lastEventID := ""
+	retry := defaultSSERetry
+
+	err := readSSEStream(strings.NewReader(stream), func(ev Event) error {
+		events = append(events, ev)
+		return nil
+	}, &lastEventID, &retry)
+	if err != nil {
+		t.Fatalf("readSSEStream: %v", err)
+	}
+	if len(events) != 1 || events[0].Data != "unterminated" {
+		t.Fatalf("expected trailing event to be flushed, got %+v", events)
+	}
+}
+
+func TestReadSSEStreamIgnoresCommentLines(t *testing.T) {
+	stream := ": this is a comment\n" +
+		"data: payload\n" +
+		"\n"
+
+	var events []Event
+	lastEventID := ""
+	retry := defaultSSERetry
+
+	err := readSSEStream(strings.NewReader(stream), func(ev Event) error {
+		events = append(events, ev)
+		return nil
+	}, &lastEventID, &retry)
+	if err != nil {
+		t.Fatalf("readSSEStream: %v", err)
+	}
+	if len(events) != 1 || events[0].Data != "payload" {
+		t.Fatalf("expected comment line to be ignored, got %+v", events)
+	}
+}
+
+func TestReadSSEStreamStopsOnHandlerStopError(t *testing.T) {
+	stream := "data: first\n\ndata: second\n\n"
+
+	var events []Event
+	lastEventID := ""
+	retry := defaultSSERetry
+
+	err := readSSEStream(strings.NewReader(stream), func(ev Event) error {
+		events = append(events, ev)
+		return ErrStopSSE
+	}, &lastEventID, &retry)
+	if err != ErrStopSSE {
+		t.Fatalf("expected ErrStopSSE to propagate, got %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected handler to stop after first event, got %d events", len(events))
+	}
+}
@@ -40,7 +40,9 @@ func defaultTransport() *http.Transport {
 
 // CustomTransport 自定义 Transport 构建器
 type TransportBuilder struct {
-	transport *http.Transport
+	transport   *http.Transport
+	proxyURLs   []string
+	proxyBypass []string
 }
 
 func NewTransport() *TransportBuilder {
@@ -72,6 +74,30 @@ func (tb *TransportBuilder) DialTimeout(d time.Duration) *TransportBuilder {
 	return tb
 }
 
+// Proxy 设置单个代理地址，支持 http(s):// 和 socks5(h):// scheme
+func (tb *TransportBuilder) Proxy(proxyURL string) *TransportBuilder {
+	tb.proxyURLs = []string{proxyURL}
+	return tb
+}
+
+// ProxyChain 设置多级代理链，依次隧道转发到最终目标
+func (tb *TransportBuilder) ProxyChain(proxyURLs ...string) *TransportBuilder {
+	tb.proxyURLs = proxyURLs
+	return tb
+}
+
+// ProxyBypass 配置无需走代理的 host（NO_PROXY 语义）
+func (tb *TransportBuilder) ProxyBypass(hosts ...string) *TransportBuilder {
+	tb.proxyBypass = hosts
+	return tb
+}
+
 func (tb *TransportBuilder) Build() *http.Transport {
+	if len(tb.proxyURLs) > 0 {
+		if dial, err := proxyDialContext(tb.proxyURLs, tb.proxyBypass); err == nil {
+			tb.transport.Proxy = nil
+			tb.transport.DialContext = dial
+		}
+	}
 	return tb.transport
 }
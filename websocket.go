@@ -0,0 +1,262 @@
+package httpx
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// wsGUID 是 RFC 6455 定义的固定 magic GUID，用于校验 Sec-WebSocket-Accept
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket 帧操作码
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// WebSocketOptions WebSocket 握手配置
+type WebSocketOptions struct {
+	// Subprotocols 通过 Sec-WebSocket-Protocol 协商的子协议
+	Subprotocols []string
+	// Headers 握手请求附加的自定义头
+	Headers http.Header
+}
+
+// WSConn 表示一个已建立的 WebSocket 连接
+type WSConn struct {
+	rwc      io.ReadWriteCloser
+	protocol string
+}
+
+// WebSocket 通过中间件链（日志/指标/熔断等均对握手请求生效）执行一次 HTTP/1.1 Upgrade 握手，
+// 返回可用于收发消息的 WSConn
+func (c *Client) WebSocket(ctx context.Context, url string, opts *WebSocketOptions) (*WSConn, error) {
+	if opts == nil {
+		opts = &WebSocketOptions{}
+	}
+
+	httpURL, err := wsToHTTPURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, httpURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create websocket request: %w", err)
+	}
+
+	for k, v := range c.opts.Headers {
+		req.Header[k] = v
+	}
+	for k, v := range opts.Headers {
+		req.Header[k] = v
+	}
+
+	key, err := generateWSKey()
+	if err != nil {
+		return nil, fmt.Errorf("generate websocket key: %w", err)
+	}
+
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	for _, p := range opts.Subprotocols {
+		req.Header.Add("Sec-WebSocket-Protocol", p)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("websocket handshake: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status, Body: body}
+	}
+	if accept := resp.Header.Get("Sec-WebSocket-Accept"); accept != expectedWSAccept(key) {
+		resp.Body.Close()
+		return nil, fmt.Errorf("httpx: invalid Sec-WebSocket-Accept header")
+	}
+
+	rwc, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		resp.Body.Close()
+		return nil, fmt.Errorf("httpx: transport does not support protocol switching")
+	}
+
+	return &WSConn{rwc: rwc, protocol: resp.Header.Get("Sec-WebSocket-Protocol")}, nil
+}
+
+// Protocol 返回服务端确认使用的子协议（如果有）
+func (ws *WSConn) Protocol() string {
+	return ws.protocol
+}
+
+// WriteMessage 写入一条文本或二进制消息。客户端发出的帧必须按 RFC 6455 做掩码处理
+func (ws *WSConn) WriteMessage(binary bool, data []byte) error {
+	opcode := byte(wsOpText)
+	if binary {
+		opcode = wsOpBinary
+	}
+	return ws.writeFrame(opcode, data)
+}
+
+// ReadMessage 读取一条完整消息（自动拼接分片帧，响应 ping/close）
+func (ws *WSConn) ReadMessage() (binary bool, data []byte, err error) {
+	for {
+		opcode, payload, err := ws.readFrame()
+		if err != nil {
+			return false, nil, err
+		}
+		switch opcode {
+		case wsOpText, wsOpBinary:
+			return opcode == wsOpBinary, payload, nil
+		case wsOpPing:
+			if err := ws.writeFrame(wsOpPong, payload); err != nil {
+				return false, nil, err
+			}
+		case wsOpPong:
+			// 忽略
+		case wsOpClose:
+			ws.writeFrame(wsOpClose, payload)
+			ws.rwc.Close()
+			return false, nil, io.EOF
+		}
+	}
+}
+
+// Close 发送关闭帧并关闭底层连接
+func (ws *WSConn) Close() error {
+	_ = ws.writeFrame(wsOpClose, nil)
+	return ws.rwc.Close()
+}
+
+func (ws *WSConn) writeFrame(opcode byte, payload []byte) error {
+	var header []byte
+	header = append(header, 0x80|opcode) // FIN=1
+
+	maskKey := make([]byte, 4)
+	if _, err := rand.Read(maskKey); err != nil {
+		return fmt.Errorf("generate mask key: %w", err)
+	}
+
+	length := len(payload)
+	switch {
+	case length <= 125:
+		header = append(header, 0x80|byte(length))
+	case length <= 65535:
+		header = append(header, 0x80|126)
+		var ext [2]byte
+		binary.BigEndian.PutUint16(ext[:], uint16(length))
+		header = append(header, ext[:]...)
+	default:
+		header = append(header, 0x80|127)
+		var ext [8]byte
+		binary.BigEndian.PutUint64(ext[:], uint64(length))
+		header = append(header, ext[:]...)
+	}
+	header = append(header, maskKey...)
+
+	masked := make([]byte, length)
+	for i, b := range payload {
+		masked[i] = b ^ maskKey[i%4]
+	}
+
+	if _, err := ws.rwc.Write(header); err != nil {
+		return err
+	}
+	_, err := ws.rwc.Write(masked)
+	return err
+}
+
+func (ws *WSConn) readFrame() (opcode byte, payload []byte, err error) {
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(ws.rwc, head); err != nil {
+		return 0, nil, err
+	}
+	opcode = head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7F)
+
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(ws.rwc, ext); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(ws.rwc, ext); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+
+	var maskKey [4]byte
+	if masked {
+		if _, err := io.ReadFull(ws.rwc, maskKey[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(ws.rwc, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= maskKey[i%4]
+		}
+	}
+	return opcode, payload, nil
+}
+
+// wsToHTTPURL 将 ws(s):// 翻译为 http(s)://，因为 net/http.Transport 只识别 http/https scheme；
+// 已经是 http(s):// 的 URL 原样透传
+func wsToHTTPURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("parse websocket url: %w", err)
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	case "http", "https":
+		// 已经是 http(s)，无需转换
+	default:
+		return "", fmt.Errorf("httpx: unsupported websocket scheme %q", u.Scheme)
+	}
+	return u.String(), nil
+}
+
+// generateWSKey 生成 16 字节随机数并做 base64 编码，作为 Sec-WebSocket-Key
+func generateWSKey() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+// expectedWSAccept 按 RFC 6455 计算期望的 Sec-WebSocket-Accept 值
+func expectedWSAccept(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,118 @@
+package httpx
+
+import (
+	"net"
+	"testing"
+)
+
+func TestWsToHTTPURLTranslatesSchemes(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"ws", "ws://example.com/socket", "http://example.com/socket", false},
+		{"wss", "wss://example.com/socket", "https://example.com/socket", false},
+		{"http passthrough", "http://example.com/socket", "http://example.com/socket", false},
+		{"https passthrough", "https://example.com/socket", "https://example.com/socket", false},
+		{"unsupported scheme", "ftp://example.com/socket", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := wsToHTTPURL(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for %q, got none", tt.in)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("wsToHTTPURL(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpectedWSAcceptMatchesRFCExample(t *testing.T) {
+	// RFC 6455 §1.3 worked example.
+	const key = "dGhlIHNhbXBsZSBub25jZQ=="
+	const want = "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="
+
+	if got := expectedWSAccept(key); got != want {
+		t.Fatalf("expectedWSAccept(%q) = %q, want %q", key, got, want)
+	}
+}
+
+func TestWSConnWriteReadFrameRoundTrip(t *testing.T) {
+	clientSide, serverSide := net.Pipe()
+	defer clientSide.Close()
+	defer serverSide.Close()
+
+	client := &WSConn{rwc: clientSide}
+	server := &WSConn{rwc: serverSide}
+
+	payload := []byte("hello websocket")
+	done := make(chan error, 1)
+	go func() {
+		done <- client.WriteMessage(false, payload)
+	}()
+
+	binary, data, err := server.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("WriteMessage: %v", err)
+	}
+	if binary {
+		t.Fatal("expected text frame, got binary")
+	}
+	if string(data) != string(payload) {
+		t.Fatalf("got payload %q, want %q", data, payload)
+	}
+}
+
+func TestWSConnPingPongFrameRoundTrip(t *testing.T) {
+	clientSide, serverSide := net.Pipe()
+	defer clientSide.Close()
+	defer serverSide.Close()
+
+	client := &WSConn{rwc: clientSide}
+	server := &WSConn{rwc: serverSide}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- client.writeFrame(wsOpPing, []byte("ping-payload"))
+	}()
+
+	opcode, payload, err := server.readFrame()
+	if err != nil {
+		t.Fatalf("readFrame: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("writeFrame: %v", err)
+	}
+	if opcode != wsOpPing || string(payload) != "ping-payload" {
+		t.Fatalf("unexpected ping frame: opcode=%d payload=%q", opcode, payload)
+	}
+
+	pongDone := make(chan error, 1)
+	go func() {
+		pongDone <- server.writeFrame(wsOpPong, payload)
+	}()
+	gotOpcode, gotPayload, err := client.readFrame()
+	if err != nil {
+		t.Fatalf("readFrame pong: %v", err)
+	}
+	if err := <-pongDone; err != nil {
+		t.Fatalf("writeFrame pong: %v", err)
+	}
+	if gotOpcode != wsOpPong || string(gotPayload) != "ping-payload" {
+		t.Fatalf("unexpected pong frame: opcode=%d payload=%q", gotOpcode, gotPayload)
+	}
+}